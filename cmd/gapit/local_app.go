@@ -0,0 +1,55 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/gapid/core/os/process"
+	"github.com/google/gapid/gapii/client"
+)
+
+// localAppFlags holds the `-local-app` specific flags: a local-app trace
+// targets a host executable directly instead of launching something on an
+// attached device. The trace verb's flag struct (trace.go) does not embed
+// localAppFlags, so these flags are not parsed by the trace verb.
+type localAppFlags struct {
+	// LocalApp is the path to the host executable to trace. runLocalApp
+	// bypasses ADB/JDWP entirely and spawns LocalApp with the GAPII
+	// libraries injected, rather than connecting to an Android device.
+	LocalApp string `help:"Trace a native host executable instead of an Android package"`
+}
+
+// runLocalApp resolves the host pkgdata layout and hands the executable
+// plus its arguments to the -local-app loader. The trace verb's Run does not
+// call it.
+func runLocalApp(ctx context.Context, v localAppFlags, args []string) error {
+	layout, err := resolveLocalAppLayout(ctx, os.Getenv("GAPID_ROOT"))
+	if err != nil {
+		return err
+	}
+	return client.TraceLocalApp(ctx, layout, v.LocalApp, args)
+}
+
+// resolveLocalAppLayout returns the pkgdata layout used to find the host
+// build of libgapii/libinterceptor for a -local-app trace.
+func resolveLocalAppLayout(ctx context.Context, gapidRoot string) (process.HostLayout, error) {
+	if gapidRoot == "" {
+		return process.HostLayout{}, fmt.Errorf("GAPID root not set, cannot locate host GAPII libraries")
+	}
+	return process.HostLayout{Root: gapidRoot}, nil
+}
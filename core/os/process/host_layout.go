@@ -0,0 +1,117 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package process locates the host build of the GAPII libraries used to
+// trace a native host executable (the `-local-app` trace path), as opposed
+// to the JDWP-based flow used for Android APKs. It does not spawn processes.
+package process
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/google/gapid/core/os/device"
+)
+
+// HostLayout is a pkgdata-style layout describing where the host build of
+// GAPID keeps the shared libraries needed to trace a native process: the
+// interception library (libinterceptor) and the tracer itself (libgapii),
+// one pair per supported host ABI.
+//
+// The directory structure mirrors gapidapk.APK's Android pkgdata layout:
+//
+//	<Root>/<os>-<architecture>/libinterceptor.<ext>
+//	<Root>/<os>-<architecture>/libgapii.<ext>
+type HostLayout struct {
+	// Root is the base pkgdata directory.
+	Root string
+}
+
+// HostABI returns the device.ABI of the machine gapit is running on.
+func HostABI() (*device.ABI, error) {
+	var arch device.Architecture
+	switch runtime.GOARCH {
+	case "amd64":
+		arch = device.X86_64
+	default:
+		return nil, fmt.Errorf("Unsupported host architecture %v", runtime.GOARCH)
+	}
+
+	var os device.OSKind
+	switch runtime.GOOS {
+	case "linux":
+		os = device.Linux
+	case "darwin":
+		os = device.OSX
+	case "windows":
+		os = device.Windows
+	default:
+		return nil, fmt.Errorf("Unsupported host OS %v", runtime.GOOS)
+	}
+
+	return &device.ABI{
+		Name:         fmt.Sprintf("%v_%v", runtime.GOOS, runtime.GOARCH),
+		OS:           os,
+		Architecture: arch,
+	}, nil
+}
+
+// osDirName returns the pkgdata directory tag for the given OS kind,
+// matching the tags HostABI assigns for the host it's running on.
+func osDirName(os device.OSKind) string {
+	switch os {
+	case device.Linux:
+		return "linux"
+	case device.OSX:
+		return "darwin"
+	case device.Windows:
+		return "windows"
+	default:
+		return os.String()
+	}
+}
+
+// dirForABI returns the pkgdata subdirectory holding the libraries for abi.
+func (l HostLayout) dirForABI(abi *device.ABI) string {
+	return filepath.Join(l.Root, fmt.Sprintf("%v-%v", osDirName(abi.OS), abi.Architecture))
+}
+
+// libExt returns the shared-library file extension for the host OS.
+func libExt() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "dylib"
+	case "windows":
+		return "dll"
+	default:
+		return "so"
+	}
+}
+
+// libName returns the shared-library file name for the host OS, given the
+// library's base name (without the "lib" prefix or extension).
+func libName(base string) string {
+	return "lib" + base + "." + libExt()
+}
+
+// LibInterceptorPath returns the path to libinterceptor for abi.
+func (l HostLayout) LibInterceptorPath(abi *device.ABI) string {
+	return filepath.Join(l.dirForABI(abi), libName("interceptor"))
+}
+
+// LibGAPIIPath returns the path to libgapii for abi.
+func (l HostLayout) LibGAPIIPath(abi *device.ABI) string {
+	return filepath.Join(l.dirForABI(abi), libName("gapii"))
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/google/gapid/core/os/device"
+)
+
+func TestHostABI(t *testing.T) {
+	abi, err := HostABI()
+	if err != nil {
+		t.Fatalf("HostABI() returned error: %v", err)
+	}
+	if abi.Architecture != device.X86_64 {
+		t.Errorf("HostABI().Architecture = %v, want %v", abi.Architecture, device.X86_64)
+	}
+}
+
+func TestLibName(t *testing.T) {
+	// libName must always keep the "lib" prefix: HostLayout's own doc
+	// comment promises <Root>/<os>-<architecture>/libinterceptor.<ext>,
+	// on every host OS including Windows.
+	for _, base := range []string{"interceptor", "gapii"} {
+		got := libName(base)
+		want := "lib" + base + "." + libExt()
+		if got != want {
+			t.Errorf("libName(%q) = %q, want %q", base, got, want)
+		}
+	}
+}
+
+func TestLibPaths(t *testing.T) {
+	layout := HostLayout{Root: "/pkgdata"}
+	abi, err := HostABI()
+	if err != nil {
+		t.Fatalf("HostABI() returned error: %v", err)
+	}
+
+	wantDir := fmt.Sprintf("/pkgdata/%v-%v", runtime.GOOS, abi.Architecture)
+
+	if got, want := layout.LibInterceptorPath(abi), wantDir+"/"+libName("interceptor"); got != want {
+		t.Errorf("LibInterceptorPath() = %q, want %q", got, want)
+	}
+	if got, want := layout.LibGAPIIPath(abi), wantDir+"/"+libName("gapii"); got != want {
+		t.Errorf("LibGAPIIPath() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/gapid/core/log"
+	"github.com/google/gapid/core/os/android/adb"
+	"github.com/google/gapid/gapidapk"
+)
+
+// bootstrapLibraryName is the native library gapid's APK repackager adds to
+// an app that opts into the bootstrap trace path, either by depending on it
+// directly or by having it side-loaded in.
+const bootstrapLibraryName = "libgapid_bootstrap.so"
+
+// hasBootstrapLibrary reports whether PackageManager's dump of gapidAPK's
+// own package (gapidAPK.Name) on d mentions GAPID's bootstrap library. The
+// traced app's package is not inspected.
+func hasBootstrapLibrary(ctx context.Context, d adb.Device, gapidAPK *gapidapk.APK) (bool, error) {
+	dump, err := d.Shell(ctx, "pm", "dump", gapidAPK.Name)
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(dump, bootstrapLibraryName), nil
+}
+
+// loadAndConnectViaBootstrap waits for the traced app's own copy of GAPID's
+// bootstrap library to start the trace, rather than forwarding a JDWP port
+// and breaking into the framework. The bootstrap library is loaded by the
+// app's own System.loadLibrary at startup (via a ContentProvider or
+// android:extractNativeLibs entry the APK repackager injects), and its
+// JNI_OnLoad does the equivalent of what loadAndConnectViaJDWP does by hand:
+// read the ABI, dlopen libinterceptor then libgapii, and set VK_LAYER_PATH,
+// all before the app touches Vulkan. That leaves nothing for the Go side to
+// do but wait for the resulting connection, which matters for
+// non-debuggable release builds and devices where `adb jdwp` is restricted.
+func (p *Process) loadAndConnectViaBootstrap(
+	ctx context.Context,
+	gapidAPK *gapidapk.APK,
+	pid int,
+	d adb.Device) error {
+
+	log.I(ctx, "Waiting for the GAPID bootstrap library to connect")
+	return p.connect(ctx)
+}
+
+// loadAndConnect picks loadAndConnectViaBootstrap over loadAndConnectViaJDWP
+// when hasBootstrapLibrary reports the bootstrap library for gapidAPK's
+// package, falling back to the general "any debuggable APK" JDWP path
+// otherwise.
+func (p *Process) loadAndConnect(
+	ctx context.Context,
+	gapidAPK *gapidapk.APK,
+	pid int,
+	d adb.Device) error {
+
+	bootstrap, err := hasBootstrapLibrary(ctx, d, gapidAPK)
+	if err != nil {
+		log.W(ctx, "Couldn't determine whether %v carries the GAPID bootstrap library: %v", gapidAPK.Name, err)
+	} else if bootstrap {
+		return p.loadAndConnectViaBootstrap(ctx, gapidAPK, pid, d)
+	}
+	return p.loadAndConnectViaJDWP(ctx, gapidAPK, pid, d)
+}
+
+// TraceAPK is the entry point for tracing an installed Android APK: it
+// connects to pid over JDWP, or to gapidAPK's own bootstrap library if it
+// reports carrying one, and waits for the resulting GAPII connection.
+func TraceAPK(ctx context.Context, gapidAPK *gapidapk.APK, pid int, d adb.Device) error {
+	return (&Process{}).loadAndConnect(ctx, gapidAPK, pid, d)
+}
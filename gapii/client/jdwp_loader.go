@@ -59,23 +59,123 @@ func waitForOnCreate(ctx context.Context, conn *jdwp.Connection, wakeup jdwp.Thr
 	return conn.WaitForMethodEntry(ctx, app.ClassID(), onCreate.ID, wakeup)
 }
 
-// waitForVulkanLoad for android.app.ApplicationLoaders.getClassLoader to be called,
-// and then suspends the thread.
-// This function is what is used to tell the vulkan loader where to search for
-// layers.
-func waitForVulkanLoad(ctx context.Context, conn *jdwp.Connection) (*jdwp.EventMethodEntry, error) {
-	loaders, err := conn.GetClassBySignature("Landroid/app/ApplicationLoaders;")
+// vulkanLoadHook describes one (class, method) JDWP break point that can be
+// used to tell the Vulkan loader where GAPID's layer lives, and how to do so
+// once the break fires. The framework has changed how (and where) this is
+// done in incompatible ways across Android releases, so loadAndConnectViaJDWP
+// tries a table of these, newest first, picking the ones valid for the
+// target's SDK_INT.
+type vulkanLoadHook struct {
+	// minSDK is the lowest android.os.Build.VERSION.SDK_INT this hook
+	// applies to.
+	minSDK             int
+	class, method, sig string
+	// inject runs on the thread suspended by the break to point the Vulkan
+	// loader at libsPath, the gapid libs directory for the process' ABI.
+	inject func(j *jdbg.JDbg, libsPath string) error
+}
+
+// concatLibrarySearchPath is the pre-Q inject callback: it appends libsPath
+// to the native library search path being assembled by
+// ApplicationLoaders.getClassLoader.
+func concatLibrarySearchPath(j *jdbg.JDbg, libsPath string) error {
+	newLibraryPath := j.String(":" + libsPath)
+	obj := j.GetStackObject("librarySearchPath").Call("concat", newLibraryPath)
+	j.SetStackObject("librarySearchPath", obj)
+	return nil
+}
+
+var vulkanLoadHooks = []vulkanLoadHook{
+	{
+		// Android Q (API 29) and later: the platform exposes
+		// GraphicsEnvironment.setupGpuLayers specifically so debuggers can
+		// add Vulkan layer search paths, so use that instead of reaching
+		// into the class loader.
+		minSDK: 29,
+		class:  "Landroid/os/GraphicsEnvironment;",
+		method: "setupGpuLayers",
+		sig:    "(Landroid/content/Context;ZLjava/lang/String;Ljava/lang/String;)V",
+		inject: func(j *jdbg.JDbg, libsPath string) error {
+			// setupGpuLayers is static; "this" is the GraphicsEnvironment
+			// class/receiver context, not a ClassLoader. setLayerPaths
+			// wants the class loader that will resolve the app's native
+			// libraries, which is the one tied to the Context argument.
+			classLoader := j.GetStackObject("context").Call("getClassLoader")
+			j.Class("android.os.GraphicsEnvironment").Call("setLayerPaths", classLoader, j.String(libsPath))
+			return nil
+		},
+	},
+	{
+		// Android N (API 24) through P (API 28): getClassLoader grew a
+		// classLoaderName/sharedLibraries pair of trailing arguments.
+		minSDK: 24,
+		class:  "Landroid/app/ApplicationLoaders;",
+		method: "getClassLoader",
+		sig:    "(Ljava/lang/String;ILjava/lang/String;Ljava/lang/String;ZLjava/lang/String;Ljava/lang/String;)Ljava/lang/ClassLoader;",
+		inject: concatLibrarySearchPath,
+	},
+	{
+		// Pre-N.
+		minSDK: 0,
+		class:  "Landroid/app/ApplicationLoaders;",
+		method: "getClassLoader",
+		sig:    "(Ljava/lang/String;IZLjava/lang/String;Ljava/lang/String;Ljava/lang/ClassLoader;)Ljava/lang/ClassLoader;",
+		inject: concatLibrarySearchPath,
+	},
+}
+
+// sdkInt reads android.os.Build$VERSION.SDK_INT over JDWP. This is a plain
+// static field read, so unlike the hooks below it needs no suspended thread.
+func sdkInt(conn *jdwp.Connection) (int, error) {
+	version, err := conn.GetClassBySignature("Landroid/os/Build$VERSION;")
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-
-	getClassLoader, err := conn.GetClassMethod(loaders.ClassID(), "getClassLoader",
-		"(Ljava/lang/String;IZLjava/lang/String;Ljava/lang/String;Ljava/lang/ClassLoader;)Ljava/lang/ClassLoader;")
+	field, err := conn.GetClassField(version.ClassID(), "SDK_INT")
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	val, err := conn.GetStaticFieldValue(version.ClassID(), field.ID)
+	if err != nil {
+		return 0, err
+	}
+	i, _ := val.(int32)
+	return int(i), nil
+}
 
-	return conn.WaitForMethodEntry(ctx, loaders.ClassID(), getClassLoader.ID, 0)
+// candidateHooksForSDK returns the vulkanLoadHooks applicable to sdk, newest
+// (highest minSDK) first. It's pure so it can be tested without a JDWP
+// connection.
+func candidateHooksForSDK(sdk int) []*vulkanLoadHook {
+	hooks := make([]*vulkanLoadHook, 0, len(vulkanLoadHooks))
+	for i := range vulkanLoadHooks {
+		if sdk >= vulkanLoadHooks[i].minSDK {
+			hooks = append(hooks, &vulkanLoadHooks[i])
+		}
+	}
+	return hooks
+}
+
+// waitForVulkanLoad waits for the first vulkanLoadHooks entry applicable to
+// sdk to fire, and suspends the thread. This is what is used to tell the
+// Vulkan loader where to search for layers.
+func waitForVulkanLoad(ctx context.Context, conn *jdwp.Connection, sdk int) (*jdwp.EventMethodEntry, *vulkanLoadHook, error) {
+	for _, hook := range candidateHooksForSDK(sdk) {
+		class, err := conn.GetClassBySignature(hook.class)
+		if err != nil {
+			continue
+		}
+		method, err := conn.GetClassMethod(class.ClassID(), hook.method, hook.sig)
+		if err != nil {
+			continue
+		}
+		event, err := conn.WaitForMethodEntry(ctx, class.ClassID(), method.ID, 0)
+		if err != nil {
+			continue
+		}
+		return event, hook, nil
+	}
+	return nil, nil, fmt.Errorf("No Vulkan load hook found for SDK_INT %v", sdk)
 }
 
 // loadAndConnectViaJDWP connects to the application waiting for a JDWP
@@ -142,28 +242,30 @@ func (p *Process) loadAndConnectViaJDWP(
 
 	classLoaderThread := jdwp.ThreadID(0)
 
-	log.I(ctx, "Waiting for ApplicationLoaders.getClassLoader()")
-	getClassLoader, err := waitForVulkanLoad(ctx, conn)
+	sdk, err := sdkInt(conn)
+	if err != nil {
+		log.W(ctx, "Couldn't read Build.VERSION.SDK_INT: %v. Assuming pre-N.", err)
+	}
+	ctx = log.V{"SDK_INT": sdk}.Bind(ctx)
+
+	log.I(ctx, "Waiting for the Vulkan layer path load hook")
+	vulkanLoad, hook, err := waitForVulkanLoad(ctx, conn, sdk)
 	if err == nil {
-		// If err != nil that means we could not find or break in getClassLoader
-		// so we have no vulkan support.
-		classLoaderThread = getClassLoader.Thread
-		err = jdbg.Do(conn, getClassLoader.Thread, func(j *jdbg.JDbg) error {
+		// If err != nil that means we could not find or break in any of the
+		// hooks applicable to sdk, so we have no vulkan support.
+		classLoaderThread = vulkanLoad.Thread
+		err = jdbg.Do(conn, vulkanLoad.Thread, func(j *jdbg.JDbg) error {
 			abi, err := processABI(j)
 			if err != nil {
 				return err
 			}
-			libsPath := gapidAPK.LibsPath(abi)
-			newLibraryPath := j.String(":" + libsPath)
-			obj := j.GetStackObject("librarySearchPath").Call("concat", newLibraryPath)
-			j.SetStackObject("librarySearchPath", obj)
-			return nil
+			return hook.inject(j, gapidAPK.LibsPath(abi))
 		})
 		if err != nil {
 			return log.Err(ctx, err, "JDWP failure")
 		}
 	} else {
-		log.W(ctx, "Couldn't break in ApplicationLoaders.getClassLoader. Vulkan will not be supported.")
+		log.W(ctx, "Couldn't find a Vulkan layer path load hook. Vulkan will not be supported.")
 	}
 
 	// Wait for Application.onCreate to be called.
@@ -0,0 +1,43 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "testing"
+
+func TestCandidateHooksForSDK(t *testing.T) {
+	for _, test := range []struct {
+		sdk        int
+		wantClass  string
+		wantMethod string
+		wantCount  int
+	}{
+		{sdk: 0, wantClass: "Landroid/app/ApplicationLoaders;", wantMethod: "getClassLoader", wantCount: 1},
+		{sdk: 24, wantClass: "Landroid/app/ApplicationLoaders;", wantMethod: "getClassLoader", wantCount: 2},
+		{sdk: 29, wantClass: "Landroid/os/GraphicsEnvironment;", wantMethod: "setupGpuLayers", wantCount: 3},
+		{sdk: 35, wantClass: "Landroid/os/GraphicsEnvironment;", wantMethod: "setupGpuLayers", wantCount: 3},
+	} {
+		hooks := candidateHooksForSDK(test.sdk)
+		if len(hooks) != test.wantCount {
+			t.Errorf("candidateHooksForSDK(%v) returned %v hooks, want %v", test.sdk, len(hooks), test.wantCount)
+			continue
+		}
+		if got := hooks[0].class; got != test.wantClass {
+			t.Errorf("candidateHooksForSDK(%v)[0].class = %v, want %v", test.sdk, got, test.wantClass)
+		}
+		if got := hooks[0].method; got != test.wantMethod {
+			t.Errorf("candidateHooksForSDK(%v)[0].method = %v, want %v", test.sdk, got, test.wantMethod)
+		}
+	}
+}
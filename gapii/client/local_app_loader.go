@@ -0,0 +1,124 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/google/gapid/core/event/task"
+	"github.com/google/gapid/core/log"
+	"github.com/google/gapid/core/os/process"
+)
+
+// TraceLocalApp is the entry point for the `-local-app` trace path: it
+// spawns exe (with args) with the GAPII libraries injected, bypassing
+// ADB/JDWP entirely, and waits for it to connect back.
+func TraceLocalApp(ctx context.Context, layout process.HostLayout, exe string, args []string) error {
+	return (&Process{}).loadAndConnectLocalApp(ctx, layout, exe, args)
+}
+
+// loadAndConnectLocalApp spawns exe (with args) as a child process with the
+// GAPII libraries injected via the dynamic loader, and waits for it to
+// connect back over the socket set up by p.connect.
+//
+// Unlike loadAndConnectViaJDWP, there's no APK, no ADB and no JDWP
+// connection to break into: the traced binary is a native host executable,
+// so gapit just needs to make sure libinterceptor and libgapii are loaded
+// before the target touches Vulkan, which the dynamic loader's preload
+// mechanism already gives us for free.
+func (p *Process) loadAndConnectLocalApp(
+	ctx context.Context,
+	layout process.HostLayout,
+	exe string,
+	args []string) error {
+
+	if runtime.GOOS == "windows" {
+		// There's no LD_PRELOAD/DYLD_INSERT_LIBRARIES equivalent on
+		// Windows, so there's nothing that would load GAPII into exe here.
+		return fmt.Errorf("-local-app tracing is not supported on Windows")
+	}
+
+	abi, err := process.HostABI()
+	if err != nil {
+		return log.Err(ctx, err, "Resolving host ABI")
+	}
+
+	interceptorPath := layout.LibInterceptorPath(abi)
+	gapiiPath := layout.LibGAPIIPath(abi)
+	ctx = log.V{"gapii.so": gapiiPath, "host abi": abi.Name}.Bind(ctx)
+
+	cmd := exec.CommandContext(ctx, exe, args...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), preloadEnv(interceptorPath, gapiiPath)...)
+
+	ctx, stop := task.WithCancel(ctx)
+	defer stop()
+
+	// Connect to GAPII.
+	// This has to be done on a separate go-routine as it blocks until the
+	// spawned process connects back.
+	connErr := make(chan error, 1)
+	go func() { connErr <- p.connect(ctx) }()
+
+	log.I(ctx, "Spawning %v with GAPII preloaded", exe)
+	if err := cmd.Start(); err != nil {
+		return log.Err(ctx, err, "Starting local app")
+	}
+
+	// Watch for the process exiting before it ever connects (missing libs,
+	// crash before touching Vulkan, ...), so that case doesn't just hang on
+	// <-connErr forever.
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case err := <-connErr:
+		return err
+	case err := <-exited:
+		stop() // p.connect is never going to get a connection now.
+		if err == nil {
+			err = fmt.Errorf("process exited before connecting to GAPII")
+		}
+		return log.Err(ctx, err, "Local app exited before connecting")
+	}
+}
+
+// preloadEnv returns the environment variables that make the OS loader
+// inject interceptorPath and gapiiPath into a freshly spawned process, and
+// point the Vulkan loader at the directory holding them.
+//
+// Only called for linux/darwin hosts: loadAndConnectLocalApp rejects
+// windows before it gets here, since there's no LD_PRELOAD equivalent to
+// use below.
+func preloadEnv(interceptorPath, gapiiPath string) []string {
+	layerDir := filepath.Dir(gapiiPath)
+	if runtime.GOOS == "darwin" {
+		return []string{
+			"DYLD_INSERT_LIBRARIES=" + interceptorPath + ":" + gapiiPath,
+			"VK_LAYER_PATH=" + layerDir,
+			"VK_INSTANCE_LAYERS=VK_LAYER_GOOGLE_gapid",
+		}
+	}
+	return []string{
+		"LD_PRELOAD=" + interceptorPath + ":" + gapiiPath,
+		"VK_LAYER_PATH=" + layerDir,
+		"VK_INSTANCE_LAYERS=VK_LAYER_GOOGLE_gapid",
+	}
+}
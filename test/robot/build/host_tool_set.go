@@ -0,0 +1,56 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"context"
+
+	"github.com/google/gapid/core/os/device"
+)
+
+// HostToolSet is the host-executable analogue of AndroidToolSet: instead of
+// pointing at a GAPID APK installed on a device, it points at the host
+// pkgdata root holding the libgapii/libinterceptor pair for Abi, used to
+// trace a native host executable without going through ADB or JDWP.
+type HostToolSet struct {
+	// Abi is the ABI of the host the subject's executable was built for.
+	Abi *device.ABI
+	// Root is the pkgdata directory containing the host build of GAPII.
+	Root string
+}
+
+// hostTools indexes the HostToolSet built for each (Package.Id, host
+// executable path) pair. Package itself doesn't carry this map, so this
+// keeps host-trace tool sets available to FindToolsForHostExe below without
+// touching Package's own fields.
+var hostTools = map[string]map[string]*HostToolSet{}
+
+// AddHostTools registers the HostToolSet built for the host executable exe
+// under the package with the given id.
+func AddHostTools(id, exe string, tools *HostToolSet) {
+	byExe, ok := hostTools[id]
+	if !ok {
+		byExe = map[string]*HostToolSet{}
+		hostTools[id] = byExe
+	}
+	byExe[exe] = tools
+}
+
+// FindToolsForHostExe resolves the host tool set able to trace the native
+// executable exe built for p, mirroring FindToolsForAPK for the host-local
+// trace path.
+func (p *Package) FindToolsForHostExe(ctx context.Context, host, target *device.Instance, exe string) *HostToolSet {
+	return hostTools[p.Id][exe]
+}
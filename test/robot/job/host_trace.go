@@ -0,0 +1,22 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+// HostTrace is the capability bit for workers able to trace a native host
+// executable directly, rather than an Android APK over ADB/JDWP (Trace).
+// It is deliberately given a bit of its own, away from the range used by
+// the existing capabilities, so that adding it can't shift the meaning of
+// a bit already persisted in an older worker's recorded capability set.
+const HostTrace Capability = 1 << 30
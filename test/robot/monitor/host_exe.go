@@ -0,0 +1,33 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+// hostExes indexes the native host executable path registered for each
+// Subject, keyed by Subject.Id. Subject itself doesn't carry this field, so
+// this keeps it available to GetHostExe below without touching Subject's
+// own fields.
+var hostExes = map[string]string{}
+
+// SetHostExe records exe as the native host executable for the subject
+// with the given id.
+func SetHostExe(id, exe string) {
+	hostExes[id] = exe
+}
+
+// GetHostExe returns the path to s's native host executable, or "" if s
+// isn't a host-exe subject.
+func (s *Subject) GetHostExe() string {
+	return hostExes[s.Id]
+}
@@ -37,7 +37,27 @@ func (s schedule) getTraceTargetTools(ctx context.Context, subj *monitor.Subject
 	return tools
 }
 
+func (s schedule) getHostTraceTargetTools(ctx context.Context, subj *monitor.Subject) *build.HostToolSet {
+	ctx = log.V{"target": s.worker.Target}.Bind(ctx)
+	tools := s.pkg.FindToolsForHostExe(ctx, s.data.FindDevice(s.worker.Host), s.data.FindDevice(s.worker.Target), subj.GetHostExe())
+
+	if tools == nil {
+		return nil
+	}
+	if tools.Root == "" {
+		return nil
+	}
+	return tools
+}
+
 func (s schedule) doTrace(ctx context.Context, subj *monitor.Subject) error {
+	// A subject whose artifact is a native host executable is traced by
+	// spawning it directly on the worker, not by installing and launching
+	// an APK over ADB, so it gets routed to a separate capability/tool set.
+	if subj.GetHostExe() != "" {
+		return s.doHostTrace(ctx, subj)
+	}
+
 	if !s.worker.Supports(job.Trace) {
 		return nil
 	}
@@ -69,3 +89,35 @@ func (s schedule) doTrace(ctx context.Context, subj *monitor.Subject) error {
 	go s.managers.Trace.Do(ctx, action.Target, input)
 	return nil
 }
+
+func (s schedule) doHostTrace(ctx context.Context, subj *monitor.Subject) error {
+	if !s.worker.Supports(job.HostTrace) {
+		return nil
+	}
+	ctx = log.Enter(ctx, "HostTrace")
+	ctx = log.V{"Package": s.pkg.Id}.Bind(ctx)
+	hostTools := s.getHostTools(ctx)
+	targetTools := s.getHostTraceTargetTools(ctx, subj)
+	if hostTools == nil || targetTools == nil {
+		return log.Err(ctx, nil, "Failed to find tools for host trace!")
+	}
+	input := &trace.Input{
+		Subject: subj.Id,
+		Gapit:   hostTools.Host.Gapit,
+		Hints:   subj.Hints,
+		Layout: &trace.HostLayout{
+			Root: targetTools.Root,
+		},
+	}
+	action := &trace.Action{
+		Input:  input,
+		Host:   s.worker.Host,
+		Target: s.worker.Target,
+	}
+	if _, found := s.data.Traces.FindOrCreate(ctx, action); found {
+		return nil
+	}
+	// TODO: we just ignore the error right now, what should we do?
+	go s.managers.Trace.Do(ctx, action.Target, input)
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2017 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+// HostLayout is the Input.Layout variant used for a host-local trace. In
+// place of ToolingLayout's on-device GapidAbi, it carries the pkgdata root
+// on the worker machine where the matching libgapii/libinterceptor pair for
+// the traced executable's ABI can be found.
+type HostLayout struct {
+	Root string
+}